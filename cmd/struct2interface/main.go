@@ -0,0 +1,35 @@
+// Command struct2interface generates a Go interface for one or more
+// structs, driven by a struct2interface.yaml config file.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+
+	"github.com/hashwky/struct2interface"
+)
+
+func main() {
+	configPath := flag.String("config", "struct2interface.yaml", "path to a struct2interface.yaml config file")
+	flag.Parse()
+
+	cfg, err := struct2interface.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	outputs, err := struct2interface.MakeFromConfig(cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for out, code := range outputs {
+		if err := ioutil.WriteFile(out, code, 0644); err != nil {
+			log.Fatalf("writing %s: %v", out, err)
+		}
+		fmt.Println("wrote", filepath.Clean(out))
+	}
+}
@@ -0,0 +1,265 @@
+package struct2interface
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Target describes one struct to generate an interface for under
+// config-driven mode, the per-entry analogue of a gqlgen models
+// mapping entry in gqlgen.yml.
+type Target struct {
+	// Struct is the name of the struct to generate an interface
+	// for.
+	Struct string `yaml:"struct"`
+	// InterfaceName overrides the generated interface's name,
+	// which otherwise defaults to "<Struct>Interface".
+	InterfaceName string `yaml:"interface_name"`
+	// OutputFile is the path results for this target are written
+	// to. Targets sharing an OutputFile are appended to the same
+	// buffer in Config.Targets order. Defaults to
+	// "<struct name>_interface.go".
+	OutputFile string `yaml:"output_file"`
+	// Package overrides the package name the interface is
+	// declared under, which otherwise defaults to the package the
+	// struct itself was declared in.
+	Package string `yaml:"package"`
+	// IncludeMethods, if non-empty, restricts the generated
+	// interface to methods whose name matches one of these
+	// path.Match-style glob patterns.
+	IncludeMethods []string `yaml:"include_methods"`
+	// ExcludeMethods drops methods whose name matches one of
+	// these path.Match-style glob patterns, applied after
+	// IncludeMethods.
+	ExcludeMethods []string `yaml:"exclude_methods"`
+	// IncludeUnexported also emits unexported methods, which
+	// ParseStruct and LoadMethodSet otherwise both filter out.
+	IncludeUnexported bool `yaml:"include_unexported"`
+	// Comment is this target's own doc comment, placed directly
+	// above its "type X interface" line. It defaults to empty, not
+	// Config.Comment, since Config.Comment is already printed once
+	// as the output file's banner comment - reusing it here as well
+	// would print the same line twice above a file with one target.
+	Comment string `yaml:"comment"`
+}
+
+// Config is the shape of a struct2interface.yaml file: the files or
+// go/packages patterns the targets are collected from, the banner
+// comment printed once at the top of each generated output file, and
+// the list of per-struct targets to emit. Files and Patterns are
+// mutually exclusive; Patterns takes precedence when both are set.
+type Config struct {
+	Files    []string `yaml:"files"`
+	Patterns []string `yaml:"patterns"`
+	Comment  string   `yaml:"comment"`
+	Targets  []Target `yaml:"targets"`
+}
+
+// LoadConfig reads and parses a struct2interface.yaml file at path.
+func LoadConfig(configPath string) (*Config, error) {
+	data, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("struct2interface: reading config %s: %w", configPath, err)
+	}
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("struct2interface: parsing config %s: %w", configPath, err)
+	}
+	return &cfg, nil
+}
+
+// outputGroup accumulates the targets destined for a single OutputFile,
+// merging their methods/order/ifaceNames/ifaceComments into the maps
+// MakeInterface expects so that one file sharing several targets gets a
+// single package/import header with one interface body per target,
+// rather than several independently-rendered files concatenated
+// together.
+type outputGroup struct {
+	pkgName       string
+	order         []StructMethod
+	methods       map[string][]string
+	ifaceNames    map[string]string
+	ifaceComments map[string][]string
+	typeParams    map[string]string
+}
+
+// MakeFromConfig generates one interface per cfg.Target, grouped by
+// resolved OutputFile, and returns a map of output path to generated
+// source ready to be written out one file per group. Each target's
+// methods are collected independently by collectTargetMethods (via
+// ParseStruct for cfg.Files, or the go/types method set for
+// cfg.Patterns) and narrowed by
+// IncludeMethods/ExcludeMethods/IncludeUnexported, then targets sharing
+// an OutputFile are merged into a single MakeInterface call so the
+// result is one well-formed file instead of several files' worth of
+// package/import blocks stitched together.
+func MakeFromConfig(cfg *Config) (map[string][]byte, error) {
+	groups := make(map[string]*outputGroup)
+	var outOrder []string
+
+	for _, t := range cfg.Targets {
+		pkgName, order, typeParam, err := collectTargetMethods(cfg, t.Struct, t.IncludeUnexported)
+		if err != nil {
+			return nil, err
+		}
+
+		order = filterMethods(order, t)
+		if len(order) == 0 {
+			continue
+		}
+		sortMethods(order, SourceOrder)
+
+		ifaceName := t.InterfaceName
+		if ifaceName == "" {
+			ifaceName = t.Struct + "Interface"
+		}
+
+		out := t.OutputFile
+		if out == "" {
+			out = t.Struct + "_interface.go"
+		}
+
+		g, ok := groups[out]
+		if !ok {
+			g = &outputGroup{
+				pkgName:       pkgName,
+				methods:       make(map[string][]string),
+				ifaceNames:    make(map[string]string),
+				ifaceComments: make(map[string][]string),
+				typeParams:    make(map[string]string),
+			}
+			groups[out] = g
+			outOrder = append(outOrder, out)
+		}
+		if t.Package != "" {
+			g.pkgName = t.Package
+		}
+
+		g.order = append(g.order, order...)
+		for _, sm := range order {
+			g.methods[sm.Struct] = append(g.methods[sm.Struct], sm.Method.Lines()...)
+		}
+		g.ifaceNames[t.Struct] = ifaceName
+		g.ifaceComments[t.Struct] = renderDoc(plainDoc, t.Comment)
+		if typeParam != "" {
+			g.typeParams[t.Struct] = typeParam
+		}
+	}
+
+	result := make(map[string][]byte)
+	for _, out := range outOrder {
+		g := groups[out]
+		code, err := MakeInterface(cfg.Comment, g.pkgName, "", g.ifaceComments, g.methods, g.order, g.typeParams, g.ifaceNames, nil)
+		if err != nil {
+			return nil, fmt.Errorf("struct2interface: generating %s: %w", out, err)
+		}
+		result[out] = code
+	}
+
+	return result, nil
+}
+
+// collectTargetMethods resolves structName's methods, and its type
+// parameters if it's generic, from whichever source cfg specifies:
+// findMethodSet's go/types-backed lookup (the same search findMethodSet
+// runs for LoadMethodSet) for cfg.Patterns, falling back to parsing
+// cfg.Files with ParseStruct. includeUnexported is forwarded to both so
+// that Target.IncludeUnexported actually has methods left for
+// filterMethods to keep.
+func collectTargetMethods(cfg *Config, structName string, includeUnexported bool) (pkgName string, order []StructMethod, typeParams string, err error) {
+	if len(cfg.Patterns) > 0 {
+		pkgs, err := loadPackages(cfg.Patterns)
+		if err != nil {
+			return "", nil, "", err
+		}
+		if pkgName, order, typeParams, ok := findMethodSet(pkgs, structName, includeUnexported); ok {
+			return pkgName, order, typeParams, nil
+		}
+		return "", nil, "", fmt.Errorf("struct2interface: struct %s not found in %v", structName, cfg.Patterns)
+	}
+
+	for _, f := range cfg.Files {
+		src, err := ioutil.ReadFile(f)
+		if err != nil {
+			return "", nil, "", err
+		}
+		pkg, _, fileOrder, _, _, parsedTypeParams, err := ParseStruct(f, src, false, "", includeUnexported)
+		if err != nil {
+			return "", nil, "", err
+		}
+		var matched []StructMethod
+		for _, sm := range fileOrder {
+			if sm.Struct == structName {
+				matched = append(matched, sm)
+			}
+		}
+		if tp, ok := parsedTypeParams[structName]; ok {
+			typeParams = tp
+		}
+		if len(matched) > 0 {
+			order = append(order, matched...)
+			pkgName = pkg
+		}
+	}
+	if len(order) == 0 {
+		return "", nil, "", fmt.Errorf("struct2interface: struct %s not found in %v", structName, cfg.Files)
+	}
+	// Each file's weights start at zero; renumber across the merged
+	// set so SourceOrder keeps file order, then position.
+	for i := range order {
+		order[i].Method.Weight = i * weightStep
+	}
+	return pkgName, order, typeParams, nil
+}
+
+// filterMethods narrows order down to the methods t wants: exported
+// methods only unless IncludeUnexported is set, then IncludeMethods
+// (keep only matches, if set) and ExcludeMethods (drop matches).
+func filterMethods(order []StructMethod, t Target) []StructMethod {
+	var kept []StructMethod
+	for _, sm := range order {
+		name := methodName(sm.Method.Code)
+
+		if !t.IncludeUnexported && !isExportedMethodName(name) {
+			continue
+		}
+		if len(t.IncludeMethods) > 0 && !matchesAny(t.IncludeMethods, name) {
+			continue
+		}
+		if matchesAny(t.ExcludeMethods, name) {
+			continue
+		}
+
+		kept = append(kept, sm)
+	}
+	return kept
+}
+
+// methodName extracts the method name from a Method.Code string such
+// as "Get(id string) (*User, error)".
+func methodName(code string) string {
+	if i := strings.IndexByte(code, '('); i >= 0 {
+		return code[:i]
+	}
+	return code
+}
+
+func isExportedMethodName(name string) bool {
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(r)
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, err := path.Match(p, name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
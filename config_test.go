@@ -0,0 +1,119 @@
+package struct2interface
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+const readerFixture = `package demo
+
+type Reader struct{}
+
+func (r *Reader) Read() (string, error) { return "", nil }
+
+func (r *Reader) private() {}
+`
+
+const writerFixture = `package demo
+
+type Writer struct{}
+
+func (w *Writer) Write(data string) error { return nil }
+`
+
+func TestMakeFromConfigMergesTargetsSharingAnOutputFile(t *testing.T) {
+	dir := t.TempDir()
+	readerFile := writeFixture(t, dir, "reader.go", readerFixture)
+	writerFile := writeFixture(t, dir, "writer.go", writerFixture)
+
+	cfg := &Config{
+		Files:   []string{readerFile, writerFile},
+		Comment: "generated",
+		Targets: []Target{
+			{Struct: "Reader", OutputFile: "io_interfaces.go", IncludeUnexported: true},
+			{Struct: "Writer", OutputFile: "io_interfaces.go"},
+		},
+	}
+
+	result, err := MakeFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("MakeFromConfig: %v", err)
+	}
+	code, ok := result["io_interfaces.go"]
+	if !ok {
+		t.Fatalf("expected output keyed by io_interfaces.go, got keys %v", keysOf(result))
+	}
+	out := string(code)
+
+	if n := strings.Count(out, "package demo"); n != 1 {
+		t.Fatalf("expected exactly one package declaration when two targets share an output file, got %d:\n%s", n, out)
+	}
+	if !strings.Contains(out, "ReaderInterface interface") || !strings.Contains(out, "WriterInterface interface") {
+		t.Fatalf("expected both ReaderInterface and WriterInterface bodies in the merged file:\n%s", out)
+	}
+	if !strings.Contains(out, "private()") {
+		t.Fatalf("expected Reader's unexported method to appear since IncludeUnexported is set:\n%s", out)
+	}
+
+	fset := token.NewFileSet()
+	if _, err := parser.ParseFile(fset, "io_interfaces.go", out, parser.AllErrors); err != nil {
+		t.Fatalf("merged output does not parse as valid Go: %v\n%s", err, out)
+	}
+}
+
+func TestMakeFromConfigTargetCommentDoesNotDuplicateBanner(t *testing.T) {
+	dir := t.TempDir()
+	readerFile := writeFixture(t, dir, "reader.go", readerFixture)
+
+	cfg := &Config{
+		Files:   []string{readerFile},
+		Comment: "generated",
+		Targets: []Target{
+			{Struct: "Reader", OutputFile: "reader_interface.go"},
+		},
+	}
+
+	result, err := MakeFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("MakeFromConfig: %v", err)
+	}
+	out := string(result["reader_interface.go"])
+
+	if n := strings.Count(out, "generated"); n != 1 {
+		t.Fatalf("expected the banner comment to appear exactly once when no Target.Comment is set, got %d:\n%s", n, out)
+	}
+}
+
+const genericStoreFixture = `package demo
+
+type Store[T any] struct{}
+
+func (s *Store[T]) Get() T {
+	var zero T
+	return zero
+}
+`
+
+func TestMakeFromConfigThreadsTypeParamsThroughPatterns(t *testing.T) {
+	withTempModule(t, map[string]string{"store.go": genericStoreFixture})
+
+	cfg := &Config{
+		Patterns: []string{"./..."},
+		Comment:  "generated",
+		Targets: []Target{
+			{Struct: "Store", OutputFile: "store_interface.go"},
+		},
+	}
+
+	result, err := MakeFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("MakeFromConfig: %v", err)
+	}
+	out := string(result["store_interface.go"])
+
+	if !strings.Contains(out, "StoreInterface[T any] interface") {
+		t.Fatalf("expected Store's type parameter to survive the patterns-based path:\n%s", out)
+	}
+}
@@ -0,0 +1,68 @@
+package struct2interface
+
+import (
+	"go/ast"
+	"go/doc"
+	"strings"
+)
+
+// plainDoc is a zero-value *doc.Package used to render comment text
+// that isn't tied to any particular parsed package - namely the
+// ifaceComment string callers pass to Make/MakeWithOptions/MakeFromTypes
+// - through the same go/doc Parser/Printer pipeline as parsed type and
+// method docs, instead of treating it as plain text.
+var plainDoc = &doc.Package{}
+
+// renderDoc formats raw doc text - already directive-stripped and
+// comment-marker-stripped, e.g. via ast.CommentGroup.Text() or a
+// doc.Type/doc.Func's Doc field - through go/doc's comment Parser and
+// Printer, the same machinery cmd/doc's pkg.go uses to render package
+// and symbol docs. This replaces the previous naive
+// strings.Replace(doc, "\n", "\n// ", -1): headings, lists and example
+// code blocks are now wrapped and indented the way godoc itself would
+// render them.
+//
+// Each returned line already carries its own "//" comment marker, so
+// callers can append the result directly into generated source instead
+// of formatting it themselves.
+func renderDoc(pkgDoc *doc.Package, raw string) []string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+
+	printer := pkgDoc.Printer()
+	printer.TextPrefix = "// "
+	printer.TextCodePrefix = "//  "
+
+	parsed := pkgDoc.Parser().Parse(raw)
+	text := printer.Text(parsed)
+	for len(text) > 0 && text[len(text)-1] == '\n' {
+		text = text[:len(text)-1]
+	}
+	if len(text) == 0 {
+		return nil
+	}
+	return strings.Split(string(text), "\n")
+}
+
+// funcDocs maps each documented method's *ast.FuncDecl to its
+// extracted, directive-stripped doc text, so callers walking a.Decls
+// can look up a method's rendered-ready doc without re-matching it to
+// pkgDoc by name and receiver.
+func funcDocs(pkgDoc *doc.Package) map[*ast.FuncDecl]string {
+	docs := make(map[*ast.FuncDecl]string)
+	for _, t := range pkgDoc.Types {
+		for _, m := range t.Methods {
+			if m.Decl != nil {
+				docs[m.Decl] = m.Doc
+			}
+		}
+	}
+	for _, f := range pkgDoc.Funcs {
+		if f.Decl != nil {
+			docs[f.Decl] = f.Doc
+		}
+	}
+	return docs
+}
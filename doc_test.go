@@ -0,0 +1,39 @@
+package struct2interface
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderDocWrapsEachLineWithCommentMarker(t *testing.T) {
+	lines := renderDoc(plainDoc, "Does the thing.")
+	if len(lines) != 1 || lines[0] != "// Does the thing." {
+		t.Fatalf("renderDoc(%q) = %#v, want a single \"// Does the thing.\" line", "Does the thing.", lines)
+	}
+}
+
+func TestRenderDocSeparatesParagraphsWithABlankCommentLine(t *testing.T) {
+	lines := renderDoc(plainDoc, "First paragraph.\n\nSecond paragraph.")
+
+	joined := strings.Join(lines, "\n")
+	if !strings.Contains(joined, "First paragraph.") || !strings.Contains(joined, "Second paragraph.") {
+		t.Fatalf("expected both paragraphs to survive rendering:\n%s", joined)
+	}
+
+	blank := -1
+	for i, l := range lines {
+		if strings.TrimSpace(strings.TrimPrefix(l, "//")) == "" {
+			blank = i
+			break
+		}
+	}
+	if blank == -1 {
+		t.Fatalf("expected go/doc's Printer to separate paragraphs with a blank comment line, got:\n%s", joined)
+	}
+}
+
+func TestRenderDocEmptyInputReturnsNil(t *testing.T) {
+	if lines := renderDoc(plainDoc, "   "); lines != nil {
+		t.Fatalf("renderDoc of blank input = %#v, want nil", lines)
+	}
+}
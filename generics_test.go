@@ -0,0 +1,66 @@
+package struct2interface
+
+import (
+	"strings"
+	"testing"
+)
+
+const genericsFixture = `package repo
+
+type Repo[T any] struct{}
+
+func (r *Repo[T]) Get(id string) (T, error) {
+	var zero T
+	return zero, nil
+}
+
+type Store[K comparable, V any] struct{}
+
+func (s *Store[K, V]) Put(key K, value V) error {
+	return nil
+}
+
+func (s *Store[K, V]) Get(key K) (V, error) {
+	var zero V
+	return zero, nil
+}
+`
+
+func TestMakeWithOptionsSingleTypeParameter(t *testing.T) {
+	dir := t.TempDir()
+	file := writeFixture(t, dir, "repo.go", genericsFixture)
+
+	code, err := MakeWithOptions([]string{file}, "generated", "", "", "", false, Options{})
+	if err != nil {
+		t.Fatalf("generating: %v", err)
+	}
+	out := string(code)
+
+	if !strings.Contains(out, "RepoInterface[T any] interface") {
+		t.Fatalf("expected a single-type-parameter interface header for Repo:\n%s", out)
+	}
+	if !strings.Contains(out, "Get(id string) (T, error)") {
+		t.Fatalf("expected Repo's Get method to keep its type parameter T:\n%s", out)
+	}
+}
+
+func TestMakeWithOptionsMultipleTypeParametersWithConstraints(t *testing.T) {
+	dir := t.TempDir()
+	file := writeFixture(t, dir, "repo.go", genericsFixture)
+
+	code, err := MakeWithOptions([]string{file}, "generated", "", "", "", false, Options{})
+	if err != nil {
+		t.Fatalf("generating: %v", err)
+	}
+	out := string(code)
+
+	if !strings.Contains(out, "StoreInterface[K comparable, V any] interface") {
+		t.Fatalf("expected a multi-type-parameter interface header for Store, with constraints preserved:\n%s", out)
+	}
+	if !strings.Contains(out, "Put(key K, value V) error") {
+		t.Fatalf("expected Store's Put method to keep its type parameters K and V:\n%s", out)
+	}
+	if !strings.Contains(out, "Get(key K) (V, error)") {
+		t.Fatalf("expected Store's Get method to keep its type parameters K and V:\n%s", out)
+	}
+}
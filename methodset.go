@@ -0,0 +1,162 @@
+package struct2interface
+
+import (
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// typesLoadMode is the packages.Load mode required to get fully
+// type-checked syntax trees, which is what go/types needs to
+// compute a method set that includes promoted methods.
+const typesLoadMode = packages.NeedName | packages.NeedTypes | packages.NeedTypesInfo |
+	packages.NeedSyntax | packages.NeedDeps | packages.NeedImports
+
+// LoadMethodSet loads the package(s) matching pattern (a go/packages
+// load pattern: a directory, an import path, or "./...") and computes
+// the full method set of structName using go/types.NewMethodSet,
+// rather than scanning FuncDecls by hand. Unlike ParseStruct, this also
+// picks up methods promoted from embedded fields, since promotion is
+// resolved during type-checking and never appears as a FuncDecl on
+// structName itself. Unexported methods are dropped unless
+// includeUnexported is set.
+//
+// The returned order is in the same source-order shape ParseStruct
+// produces (see StructMethod), with Weight left at its default gapped
+// spacing so it can be merged with other StructMethod slices and fed
+// through sortMethods/MakeInterface unchanged. typeParams is structName's
+// type parameter list formatted the same way ParseStruct formats one
+// from a TypeSpec, e.g. "T any" or "K comparable, V any", empty if
+// structName isn't generic.
+func LoadMethodSet(pattern string, structName string, includeUnexported bool) (pkgName string, order []StructMethod, typeParams string, err error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: typesLoadMode}, pattern)
+	if err != nil {
+		return "", nil, "", fmt.Errorf("struct2interface: loading %s: %w", pattern, err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return "", nil, "", fmt.Errorf("struct2interface: errors type-checking %s", pattern)
+	}
+
+	if pkgName, order, typeParams, ok := findMethodSet(pkgs, structName, includeUnexported); ok {
+		return pkgName, order, typeParams, nil
+	}
+
+	return "", nil, "", fmt.Errorf("struct2interface: struct %s not found in %s", structName, pattern)
+}
+
+// findMethodSet searches already-loaded pkgs for a struct named
+// structName and reports its method set and type parameters, or
+// ok == false if none of pkgs declare it. It's the shared search loop
+// behind LoadMethodSet, which loads a single pattern for one struct,
+// and config.go's collectTargetMethods, which resolves each Target.Struct
+// out of the same cfg.Patterns packages.
+func findMethodSet(pkgs []*packages.Package, structName string, includeUnexported bool) (pkgName string, order []StructMethod, typeParams string, ok bool) {
+	for _, pkg := range pkgs {
+		if order, typeParams, ok := methodSetFor(pkg, structName, includeUnexported); ok {
+			return pkg.Types.Name(), order, typeParams, true
+		}
+	}
+	return "", nil, "", false
+}
+
+// methodSetFor computes the ordered method set and type parameter list
+// of structName within an already-loaded pkg, or reports ok == false if
+// pkg does not declare a struct by that name. Unexported methods are
+// dropped unless includeUnexported is set. It is shared by
+// findMethodSet, which searches for one struct across possibly many
+// packages, and MakePackages, which resolves many struct names out of
+// possibly many packages without reloading the same package once per
+// struct.
+func methodSetFor(pkg *packages.Package, structName string, includeUnexported bool) (order []StructMethod, typeParams string, ok bool) {
+	obj := pkg.Types.Scope().Lookup(structName)
+	if obj == nil {
+		return nil, "", false
+	}
+	named, ok := obj.Type().(*types.Named)
+	if !ok {
+		return nil, "", false
+	}
+	if _, ok := named.Underlying().(*types.Struct); !ok {
+		return nil, "", false
+	}
+
+	// Qualifier strips the current package's own name from
+	// referenced types, mirroring what the old regex-based
+	// stripping in FormatFieldList did for the single-file
+	// parser, but driven by the type checker instead of a
+	// best-effort regex.
+	qualifier := func(p *types.Package) string {
+		if p == pkg.Types {
+			return ""
+		}
+		return p.Name()
+	}
+
+	typeParams = typeParamsString(named, qualifier)
+
+	// A method set is ordered by name; re-sort by file path, then
+	// offset, so weights follow declaration order.
+	mset := types.NewMethodSet(types.NewPointer(named))
+	var fns []*types.Func
+	for i := 0; i < mset.Len(); i++ {
+		fn, ok := mset.At(i).Obj().(*types.Func)
+		if !ok || (!includeUnexported && !fn.Exported()) {
+			continue
+		}
+		fns = append(fns, fn)
+	}
+	sort.SliceStable(fns, func(i, j int) bool {
+		a, b := pkg.Fset.Position(fns[i].Pos()), pkg.Fset.Position(fns[j].Pos())
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		return a.Offset < b.Offset
+	})
+	weight := 0
+	for _, fn := range fns {
+		pos := pkg.Fset.Position(fn.Pos())
+		sig := fn.Type().(*types.Signature)
+		code := fn.Name() + signatureString(sig, qualifier)
+		order = append(order, StructMethod{
+			Struct: structName,
+			Method: Method{
+				Code:   code,
+				Struct: structName,
+				File:   pos.Filename,
+				Pos:    pos,
+				Weight: weight,
+			},
+		})
+		weight += weightStep
+	}
+
+	return order, typeParams, true
+}
+
+// typeParamsString formats named's type parameter list the way
+// ParseStruct formats one from a TypeSpec's ast.FieldList, e.g.
+// "T any" or "K comparable, V any", so both paths feed MakeInterface's
+// typeParams argument the same shape. Returns "" if named isn't generic.
+func typeParamsString(named *types.Named, qualifier types.Qualifier) string {
+	tps := named.TypeParams()
+	if tps == nil || tps.Len() == 0 {
+		return ""
+	}
+	parts := make([]string, tps.Len())
+	for i := 0; i < tps.Len(); i++ {
+		tp := tps.At(i)
+		parts[i] = fmt.Sprintf("%s %s", tp.Obj().Name(), types.TypeString(tp.Constraint(), qualifier))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// signatureString renders sig as an interface method signature,
+// e.g. "(a int) (bool, error)", by asking go/types for the full
+// "func(...)..." string and dropping the leading "func" keyword.
+func signatureString(sig *types.Signature, qualifier types.Qualifier) string {
+	full := types.TypeString(sig, qualifier)
+	return full[len("func"):]
+}
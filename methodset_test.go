@@ -0,0 +1,95 @@
+package struct2interface
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withTempModule writes files (relative path -> source) under a fresh
+// temp directory containing its own go.mod, chdirs into it for the
+// duration of the test, and restores the original working directory on
+// cleanup. go/packages.Load resolves patterns against the process's
+// working directory, so this is the smallest fixture that lets
+// LoadMethodSet/MakePackages type-check a throwaway package.
+func withTempModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	all := make(map[string]string, len(files)+1)
+	all["go.mod"] = "module testmod\n\ngo 1.21\n"
+	for name, content := range files {
+		all[name] = content
+	}
+	for name, content := range all {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("creating %s: %v", filepath.Dir(path), err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getting working directory: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("chdir to %s: %v", dir, err)
+	}
+	t.Cleanup(func() {
+		if err := os.Chdir(cwd); err != nil {
+			t.Fatalf("restoring working directory: %v", err)
+		}
+	})
+
+	return dir
+}
+
+const embeddingFixture = `package testmod
+
+type Base struct{}
+
+func (b *Base) Hello() string { return "hi" }
+
+type Widget struct {
+	Base
+}
+
+func (w *Widget) Name() string { return "widget" }
+`
+
+func TestLoadMethodSetIncludesPromotedMethods(t *testing.T) {
+	withTempModule(t, map[string]string{"widget.go": embeddingFixture})
+
+	pkgName, order, typeParams, err := LoadMethodSet("./...", "Widget", false)
+	if err != nil {
+		t.Fatalf("LoadMethodSet: %v", err)
+	}
+	if pkgName != "testmod" {
+		t.Fatalf("pkgName = %q, want testmod", pkgName)
+	}
+	if typeParams != "" {
+		t.Fatalf("typeParams = %q, want empty for a non-generic struct", typeParams)
+	}
+
+	var names []string
+	for _, sm := range order {
+		names = append(names, methodName(sm.Method.Code))
+	}
+
+	for _, want := range []string{"Name", "Hello"} {
+		found := false
+		for _, got := range names {
+			if got == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("method set %v missing %q (promoted methods from embedded fields should be included)", names, want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package struct2interface
+
+import (
+	"fmt"
+	"go/types"
+	"log"
+	"sort"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// MakePackages resolves patterns using golang.org/x/tools/go/packages -
+// the same "go list"-style patterns "go build" accepts, e.g. "./...",
+// "github.com/foo/bar" - and generates one interface per requested struct.
+// It supersedes the ioutil.ReadFile loop in MakeWithOptions for callers
+// that don't want to enumerate every file by hand: packages.Load already
+// recurses "./..." patterns, skips vendor/ directories and (since
+// Config.Tests is left unset) _test.go files, and honors build tags, so
+// no bespoke directory walker like cmd/doc's dirs.go is needed on top of
+// it.
+//
+// If structNames is empty, every exported struct declared in the
+// resolved packages is generated. Methods declared across multiple files
+// of the same package are merged under a single interface, the same way
+// MakeWithOptions merges multiple files of one package today.
+//
+// The result maps "<package path>.<struct name>" to that struct's
+// generated interface source, so callers can write each to its own
+// output file.
+func MakePackages(patterns []string, structNames []string, comment, ifaceComment string, opts Options) (map[string][]byte, error) {
+	pkgs, err := loadPackages(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	want := make(map[string]struct{}, len(structNames))
+	for _, s := range structNames {
+		want[s] = struct{}{}
+	}
+
+	result := make(map[string][]byte)
+	for _, pkg := range pkgs {
+		names := structNamesIn(pkg, want)
+
+		for _, name := range names {
+			order, typeParam, ok := methodSetFor(pkg, name, false)
+			if !ok || len(order) == 0 {
+				continue
+			}
+			sortMethods(order, opts.Sort)
+
+			methods := make(map[string][]string)
+			for _, sm := range order {
+				methods[sm.Struct] = append(methods[sm.Struct], sm.Method.Lines()...)
+			}
+			ifaceComments := map[string][]string{name: renderDoc(plainDoc, ifaceComment)}
+
+			var typeParams map[string]string
+			if typeParam != "" {
+				typeParams = map[string]string{name: typeParam}
+			}
+
+			code, err := MakeInterface(comment, pkg.Types.Name(), "", ifaceComments, methods, order, typeParams, nil, nil)
+			if err != nil {
+				log.Println("MakeInterface failed", err)
+				return nil, err
+			}
+
+			result[pkg.PkgPath+"."+name] = code
+		}
+	}
+
+	return result, nil
+}
+
+// loadPackages loads and type-checks the packages matching patterns,
+// the shared entry point MakePackages and the config-driven dispatch in
+// config.go both use to resolve go/packages load patterns.
+func loadPackages(patterns []string) ([]*packages.Package, error) {
+	pkgs, err := packages.Load(&packages.Config{Mode: typesLoadMode}, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("struct2interface: loading %v: %w", patterns, err)
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return nil, fmt.Errorf("struct2interface: errors type-checking %v", patterns)
+	}
+	return pkgs, nil
+}
+
+// structNamesIn returns the exported struct names declared directly in
+// pkg's scope, restricted to want when it is non-empty, sorted for
+// deterministic iteration.
+func structNamesIn(pkg *packages.Package, want map[string]struct{}) []string {
+	scope := pkg.Types.Scope()
+	var names []string
+	for _, name := range scope.Names() {
+		if len(want) > 0 {
+			if _, ok := want[name]; !ok {
+				continue
+			}
+		}
+		obj := scope.Lookup(name)
+		if !obj.Exported() {
+			continue
+		}
+		named, ok := obj.Type().(*types.Named)
+		if !ok {
+			continue
+		}
+		if _, ok := named.Underlying().(*types.Struct); !ok {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
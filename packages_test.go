@@ -0,0 +1,59 @@
+package struct2interface
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+const packagesFixture = `package testmod
+
+type Alpha struct{}
+
+func (a *Alpha) Foo() string { return "foo" }
+
+type beta struct{}
+
+func (b *beta) bar() {}
+`
+
+func TestMakePackagesDiscoversExportedStructs(t *testing.T) {
+	withTempModule(t, map[string]string{"alpha.go": packagesFixture})
+
+	result, err := MakePackages([]string{"./..."}, nil, "generated", "", Options{})
+	if err != nil {
+		t.Fatalf("MakePackages: %v", err)
+	}
+
+	var key string
+	for k := range result {
+		if strings.HasSuffix(k, ".Alpha") {
+			key = k
+		}
+		if strings.HasSuffix(k, ".beta") {
+			t.Fatalf("unexported struct beta should not have been generated, got key %q", k)
+		}
+	}
+	if key == "" {
+		t.Fatalf("expected a result keyed by <pkg path>.Alpha, got keys %v", keysOf(result))
+	}
+
+	code := string(result[key])
+	if !strings.Contains(code, "AlphaInterface interface") {
+		t.Fatalf("expected the interface to be named AlphaInterface, not Alpha, to avoid colliding with the struct:\n%s", code)
+	}
+
+	fset := token.NewFileSet()
+	if _, err := parser.ParseFile(fset, key, code, parser.AllErrors); err != nil {
+		t.Fatalf("generated code for %s does not parse as valid Go: %v\n%s", key, err, code)
+	}
+}
+
+func keysOf(m map[string][]byte) []string {
+	var ks []string
+	for k := range m {
+		ks = append(ks, k)
+	}
+	return ks
+}
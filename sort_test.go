@@ -0,0 +1,76 @@
+package struct2interface
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sortFixture = `package widget
+
+type Widget struct{}
+
+func (w *Widget) Zeta() {}
+
+func (w *Widget) Alpha() {}
+
+func (w *Widget) Mu() {}
+`
+
+func writeFixture(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+	return path
+}
+
+// methodOrder reports whether each of names appears in code in the
+// given order, e.g. methodOrder(code, "Alpha", "Zeta") is true only if
+// "Alpha(" occurs before "Zeta(" occurs.
+func methodOrder(code string, names ...string) bool {
+	pos := -1
+	for _, name := range names {
+		i := strings.Index(code, name+"(")
+		if i == -1 || i <= pos {
+			return false
+		}
+		pos = i
+	}
+	return true
+}
+
+func TestMakeWithOptionsSourceOrderIsDeterministic(t *testing.T) {
+	dir := t.TempDir()
+	file := writeFixture(t, dir, "widget.go", sortFixture)
+
+	first, err := MakeWithOptions([]string{file}, "generated", "", "", "", false, Options{Sort: SourceOrder})
+	if err != nil {
+		t.Fatalf("first generation: %v", err)
+	}
+	second, err := MakeWithOptions([]string{file}, "generated", "", "", "", false, Options{Sort: SourceOrder})
+	if err != nil {
+		t.Fatalf("second generation: %v", err)
+	}
+	if string(first) != string(second) {
+		t.Fatalf("regenerating the same input twice produced different output:\n--- first ---\n%s\n--- second ---\n%s", first, second)
+	}
+	if !methodOrder(string(first), "Zeta", "Alpha", "Mu") {
+		t.Fatalf("SourceOrder should preserve declaration order Zeta, Alpha, Mu:\n%s", first)
+	}
+}
+
+func TestMakeWithOptionsAlphabeticalReordersMethods(t *testing.T) {
+	dir := t.TempDir()
+	file := writeFixture(t, dir, "widget.go", sortFixture)
+
+	alphabetical, err := MakeWithOptions([]string{file}, "generated", "", "", "", false, Options{Sort: Alphabetical})
+	if err != nil {
+		t.Fatalf("generating Alphabetical: %v", err)
+	}
+	if !methodOrder(string(alphabetical), "Alpha", "Mu", "Zeta") {
+		t.Fatalf("Alphabetical should sort methods by name regardless of declaration order:\n%s", alphabetical)
+	}
+}
@@ -9,16 +9,55 @@ import (
 	"io/ioutil"
 	"log"
 	"regexp"
+	"sort"
 	"strings"
 
 	"golang.org/x/tools/imports"
 )
 
+// SortMode controls the order in which methods are
+// emitted into the generated interface.
+type SortMode int
+
+const (
+	// SourceOrder emits methods in the order they were
+	// declared in the source files, ordered by file path
+	// and then by position within the file. This is the
+	// default and yields deterministic, diff-friendly
+	// output across repeated runs.
+	SourceOrder SortMode = iota
+	// Alphabetical sorts methods by name, ignoring where
+	// they were declared.
+	Alphabetical
+	// Grouped sorts structs alphabetically, then methods
+	// within each struct in SourceOrder.
+	Grouped
+)
+
+// weightStep is the gap left between the weights of two
+// adjacent methods, mirroring Hugo's codegen/methods.go.
+// Leaving gaps allows callers to splice in overriding
+// methods later without renumbering every weight.
+const weightStep = 1000
+
 // Method describes the code and documentation
 // tied into a method
 type Method struct {
 	Code string
 	Docs []string
+
+	// Struct is the name of the struct the method belongs to.
+	Struct string
+	// File is the path of the source file the method was
+	// declared in, used to derive a stable ordering key.
+	File string
+	// Pos is the position of the method's FuncDecl within
+	// File.
+	Pos token.Position
+	// Weight orders the method relative to its siblings.
+	// It is assigned in source order with gaps of
+	// weightStep between adjacent methods.
+	Weight int
 }
 
 // Lines return a []string consisting of
@@ -31,6 +70,15 @@ func (m *Method) Lines() []string {
 	return lines
 }
 
+// StructMethod pairs a Method with the name of the struct it
+// was collected for. ParseStruct returns these as a flat,
+// ordered slice alongside the map representation so callers
+// that care about determinism don't need to range over a map.
+type StructMethod struct {
+	Struct string
+	Method Method
+}
+
 // GetReceiverTypeName takes in the entire
 // source code and a single declaration.
 // It then checks if the declaration is a
@@ -38,10 +86,13 @@ func (m *Method) Lines() []string {
 // the GetReceiverType to check whether
 // the declaration is a method or a function
 // if it is a function we fatally stop.
-// If it is a method we retrieve the type
-// of the receiver based on the types
-// start and end pos in combination with
-// the actual source code.
+// If it is a method we retrieve the base
+// identifier of the receiver, which for a
+// generic receiver such as `*Repo[T, U]` is
+// just `Repo` - the type argument list is
+// dropped so generic methods still group
+// under the same struct name as non-generic
+// ones.
 // It then returns the name of the
 // receiver type and the function declaration
 //
@@ -56,11 +107,30 @@ func GetReceiverTypeName(src []byte, fl interface{}) (string, *ast.FuncDecl) {
 	if err != nil {
 		return "", nil
 	}
-	st := string(src[t.Pos()-1 : t.End()-1])
-	if len(st) > 0 && st[0] == '*' {
-		st = st[1:]
+	ident, ok := receiverBaseIdent(t)
+	if !ok {
+		return "", nil
+	}
+	return ident.Name, fd
+}
+
+// receiverBaseIdent unwraps a receiver type expression down to its base
+// identifier, stepping through a leading pointer (`*Repo`) and a generic
+// type argument list (`Repo[T, U]`, or `Repo[T, U, V]` once it parses as
+// an IndexListExpr) so both "*Repo" and "*Repo[T, U]" resolve to "Repo".
+func receiverBaseIdent(expr ast.Expr) (*ast.Ident, bool) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t, true
+	case *ast.StarExpr:
+		return receiverBaseIdent(t.X)
+	case *ast.IndexExpr:
+		return receiverBaseIdent(t.X)
+	case *ast.IndexListExpr:
+		return receiverBaseIdent(t.X)
+	default:
+		return nil, false
 	}
-	return st, fd
 }
 
 // GetReceiverType checks if the FuncDecl
@@ -130,13 +200,66 @@ func FormatCode(code string) ([]byte, error) {
 	return imports.Process("", []byte(code), opts)
 }
 
+// sortMethods orders sms in place according to mode. Every branch is a
+// total order (SliceStable only needs it to break remaining ties), so
+// two runs over the same input always produce the same result.
+//
+// SourceOrder sorts purely by Weight, i.e. true declaration order
+// across the whole merged set: if struct A's second method was
+// declared between struct B's methods, it stays there rather than
+// being pulled next to A's first method. Grouped sorts by Struct
+// first so every struct's methods stay contiguous, falling back to
+// Weight within a struct. Alphabetical ignores declaration order
+// entirely and sorts by method name.
+func sortMethods(sms []StructMethod, mode SortMode) {
+	sort.SliceStable(sms, func(i, j int) bool {
+		a, b := sms[i], sms[j]
+		switch mode {
+		case Alphabetical:
+			if a.Method.Code != b.Method.Code {
+				return a.Method.Code < b.Method.Code
+			}
+			return a.Struct < b.Struct
+		case Grouped:
+			if a.Struct != b.Struct {
+				return a.Struct < b.Struct
+			}
+			return a.Method.Weight < b.Method.Weight
+		default: // SourceOrder
+			return a.Method.Weight < b.Method.Weight
+		}
+	})
+}
+
 // MakeInterface takes in all of the items
 // required for generating the interface,
 // it then simply concatenates them all
 // to an array, joins this array to a string
 // with newline and passes it on to FormatCode
-// which then directly returns the result
-func MakeInterface(comment string, pkgName string, ifaceName string, ifaceComment map[string]string, methods map[string][]string, imports []string) ([]byte, error) {
+// which then directly returns the result.
+//
+// order determines the sequence structs and their methods
+// are emitted in; callers should sort it with sortMethods
+// first. Any struct present in methods but missing from
+// order (e.g. built by hand) is appended afterwards in map
+// iteration order, so existing callers keep working.
+//
+// typeParams optionally maps a struct name to its type
+// parameter list (e.g. "T any, U comparable"), which is
+// emitted as `type FooInterface[T any, U comparable] interface`
+// for generic structs; a struct absent from typeParams gets
+// a plain, non-generic interface header.
+//
+// ifaceName is the interface name to use for a struct that has
+// no more specific entry in ifaceNames (struct name -> interface
+// name); when ifaceName is also empty, a struct falls back to
+// "<struct name>Interface".
+//
+// ifaceComment holds each struct's doc comment as already-rendered,
+// "//"-prefixed lines (see renderDoc) rather than a single raw
+// string, so godoc-style wrapping and indentation survive unchanged
+// into the generated interface.
+func MakeInterface(comment string, pkgName string, ifaceName string, ifaceComment map[string][]string, methods map[string][]string, order []StructMethod, typeParams map[string]string, ifaceNames map[string]string, imports []string) ([]byte, error) {
 	output := []string{
 		"// " + comment,
 		"",
@@ -148,10 +271,37 @@ func MakeInterface(comment string, pkgName string, ifaceName string, ifaceCommen
 		")",
 		"",
 	)
-	for structName, method := range methods {
-		output = append(output, fmt.Sprintf("// %s", strings.Replace(ifaceComment[structName], "\n", "\n// ", -1)))
-		output = append(output, fmt.Sprintf("type %s interface {", structName+"Interface"))
-		output = append(output, method...)
+
+	var structOrder []string
+	seen := make(map[string]struct{})
+	for _, sm := range order {
+		if _, ok := seen[sm.Struct]; ok {
+			continue
+		}
+		seen[sm.Struct] = struct{}{}
+		structOrder = append(structOrder, sm.Struct)
+	}
+	for structName := range methods {
+		if _, ok := seen[structName]; !ok {
+			seen[structName] = struct{}{}
+			structOrder = append(structOrder, structName)
+		}
+	}
+
+	for _, structName := range structOrder {
+		header := ifaceNames[structName]
+		if header == "" {
+			header = ifaceName
+		}
+		if header == "" {
+			header = structName + "Interface"
+		}
+		if tp := typeParams[structName]; tp != "" {
+			header = fmt.Sprintf("%s[%s]", header, tp)
+		}
+		output = append(output, ifaceComment[structName]...)
+		output = append(output, fmt.Sprintf("type %s interface {", header))
+		output = append(output, methods[structName]...)
 		output = append(output, "}")
 	}
 	code := strings.Join(output, "\n")
@@ -159,27 +309,55 @@ func MakeInterface(comment string, pkgName string, ifaceName string, ifaceCommen
 	return FormatCode(code)
 }
 
-// ParseStruct takes in a piece of source code as a
-// []byte, the name of the struct it should base the
-// interface on and a bool saying whether it should
-// include docs.  It then returns an []Method where
-// Method contains the method declaration(not the code)
-// that is required for the interface and any documentation
-// if included.
+// ParseStruct takes in the path and source of a single file,
+// the name of the struct it should base the interface on and
+// a bool saying whether it should include docs. It then
+// returns a map of struct name to []Method where Method
+// contains the method declaration (not the code) that is
+// required for the interface and any documentation if
+// included. Unexported methods are dropped unless
+// includeUnexported is set.
+//
+// order carries the same methods as the methods map, but as
+// a flat slice in source order, with each Method's Weight
+// set relative to its siblings within this file. Callers
+// merging multiple files should renumber Weight so ordering
+// stays total across the merged set; see MakeWithOptions.
+//
+// typeParams maps a struct name to its type parameter list
+// rendered exactly as it should appear in the generated
+// interface header, e.g. "T any, U comparable", for structs
+// declared with `type Repo[T any, U comparable] struct{...}`.
+// Structs without type parameters are absent from the map.
+//
+// typeDoc maps a struct name to its doc comment, already rendered
+// into comment-ready lines by renderDoc - see MakeInterface.
+//
+// Method docs and type docs are both sourced from go/doc rather
+// than by slicing comment bytes out of src directly, so block
+// comments (/* ... */) are handled correctly and build directives
+// (//go:build, //line) never leak into the generated interface.
+//
 // It also returns a []string containing all of the imports
 // including their aliases regardless of them being used or
 // not, the imports not used will be removed later using the
 // 'imports' pkg If anything goes wrong, this method will
 // fatally stop the execution
-func ParseStruct(src []byte, copyTypeDocs bool, pkgName string) (pkg string, methods map[string][]Method, imports []string, typeDoc map[string]string, err error) {
+func ParseStruct(file string, src []byte, copyTypeDocs bool, pkgName string, includeUnexported bool) (pkg string, methods map[string][]Method, order []StructMethod, imports []string, typeDoc map[string][]string, typeParams map[string]string, err error) {
 	fset := token.NewFileSet()
-	a, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	a, err := parser.ParseFile(fset, file, src, parser.ParseComments)
 	if err != nil {
 		return
 	}
 
 	pkg = a.Name.Name
 
+	pkgDoc, err := doc.NewFromFiles(fset, []*ast.File{a}, pkg, doc.AllDecls)
+	if err != nil {
+		return
+	}
+	methodDocs := funcDocs(pkgDoc)
+
 	for _, i := range a.Imports {
 		if i.Name != nil {
 			imports = append(imports, fmt.Sprintf("%s %s", i.Name.String(), i.Path.Value))
@@ -188,46 +366,88 @@ func ParseStruct(src []byte, copyTypeDocs bool, pkgName string) (pkg string, met
 		}
 	}
 
+	typeParams = make(map[string]string)
+	for _, d := range a.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts, ok := spec.(*ast.TypeSpec)
+			if !ok || ts.TypeParams == nil {
+				continue
+			}
+			params := FormatFieldList(src, ts.TypeParams, pkgName)
+			typeParams[ts.Name.Name] = strings.Join(params, ", ")
+		}
+	}
+
 	methods = make(map[string][]Method)
+	weight := 0
 	for _, d := range a.Decls {
 		if a, fd := GetReceiverTypeName(src, d); a != "" {
-			if !fd.Name.IsExported() {
+			if !includeUnexported && !fd.Name.IsExported() {
 				continue
 			}
 			params := FormatFieldList(src, fd.Type.Params, pkgName)
 			ret := FormatFieldList(src, fd.Type.Results, pkgName)
-			method := fmt.Sprintf("%s(%s) (%s)", fd.Name.String(), strings.Join(params, ", "), strings.Join(ret, ", "))
+			code := fmt.Sprintf("%s(%s) (%s)", fd.Name.String(), strings.Join(params, ", "), strings.Join(ret, ", "))
 			var docs []string
-			if fd.Doc != nil {
-				for _, d := range fd.Doc.List {
-					docs = append(docs, string(src[d.Pos()-1:d.End()-1]))
-				}
+			if raw, ok := methodDocs[fd]; ok {
+				docs = renderDoc(pkgDoc, raw)
+			}
+			method := Method{
+				Code:   code,
+				Docs:   docs,
+				Struct: a,
+				File:   file,
+				Pos:    fset.Position(fd.Pos()),
+				Weight: weight,
 			}
-			methods[a] = append(methods[a], Method{
-				Code: method,
-				Docs: docs,
-			})
+			weight += weightStep
+			methods[a] = append(methods[a], method)
+			order = append(order, StructMethod{Struct: a, Method: method})
 		}
 	}
 
 	if copyTypeDocs {
-		typeDoc = make(map[string]string)
-		pkg := &ast.Package{Files: map[string]*ast.File{"": a}}
-		doc := doc.New(pkg, "", doc.AllDecls)
-		for _, t := range doc.Types {
-			typeDoc[t.Name] = strings.TrimSuffix(t.Doc, "\n")
+		typeDoc = make(map[string][]string)
+		for _, t := range pkgDoc.Types {
+			typeDoc[t.Name] = renderDoc(pkgDoc, t.Doc)
 		}
 	}
 
 	return
 }
 
+// Options controls how MakeWithOptions renders the generated
+// interface.
+type Options struct {
+	// Sort selects the method ordering strategy. The zero
+	// value is SourceOrder.
+	Sort SortMode
+}
+
+// Make keeps the historic signature and behavior (SourceOrder
+// across the given files) by delegating to MakeWithOptions.
 func Make(files []string, comment, pkgName, ifaceName, ifaceComment string, copyTypeDoc bool) ([]byte, error) {
-	allMethods := make(map[string][]string)
+	return MakeWithOptions(files, comment, pkgName, ifaceName, ifaceComment, copyTypeDoc, Options{})
+}
+
+// MakeWithOptions behaves like Make, but additionally accepts
+// Options to control method ordering. Each file's methods are
+// parsed in source order, merged, renumbered so Weight stays
+// total across the whole set, sorted according to opts.Sort,
+// and only then handed to MakeInterface - so two runs over the
+// same input produce byte-identical output.
+func MakeWithOptions(files []string, comment, pkgName, ifaceName, ifaceComment string, copyTypeDoc bool, opts Options) ([]byte, error) {
+	var allOrder []StructMethod
 	allImports := []string{}
 	mset := make(map[string]struct{})
 	iset := make(map[string]struct{})
-	typeDoc := make(map[string]string)
+	typeDoc := make(map[string][]string)
+	allTypeParams := make(map[string]string)
+	ifaceHeader := renderDoc(plainDoc, ifaceComment)
 	pkgName = ""
 
 	for _, f := range files {
@@ -236,7 +456,7 @@ func Make(files []string, comment, pkgName, ifaceName, ifaceComment string, copy
 			return nil, err
 		}
 
-		pkg, methods, imports, parsedTypeDoc, err := ParseStruct(src, copyTypeDoc, pkgName)
+		pkg, methods, order, imports, parsedTypeDoc, parsedTypeParams, err := ParseStruct(f, src, copyTypeDoc, pkgName, false)
 		if err != nil {
 			log.Println("file:", f)
 			return nil, err
@@ -255,28 +475,86 @@ func Make(files []string, comment, pkgName, ifaceName, ifaceComment string, copy
 			}
 		}
 
-		for structName, mm := range methods {
-			typeDoc[structName] = fmt.Sprintf("%s\n%s", ifaceComment, parsedTypeDoc[structName])
+		for structName := range methods {
+			typeDoc[structName] = append(append([]string{}, ifaceHeader...), parsedTypeDoc[structName]...)
+			if tp, ok := parsedTypeParams[structName]; ok {
+				allTypeParams[structName] = tp
+			}
+		}
 
-			for _, m := range mm {
-				if _, ok := mset[m.Code]; !ok {
-					allMethods[structName] = append(allMethods[structName], m.Lines()...)
-					mset[m.Code] = struct{}{}
-				}
+		for _, sm := range order {
+			if _, ok := mset[sm.Method.Code]; ok {
+				continue
 			}
+			mset[sm.Method.Code] = struct{}{}
+			allOrder = append(allOrder, sm)
 		}
 	}
 
+	// allOrder was built by appending each file's methods in
+	// turn, already in (file, position) order, so renumbering
+	// by index preserves that order while keeping the gaps
+	// that let SortMode implementations splice methods in.
+	for i := range allOrder {
+		allOrder[i].Method.Weight = i * weightStep
+	}
+
+	sortMethods(allOrder, opts.Sort)
+
+	// Rebuild allMethods from allOrder, which has just been sorted
+	// per opts.Sort, rather than from the raw per-file parse order
+	// gathered above - otherwise the emitted method bodies would
+	// never reflect opts.Sort at all.
+	allMethods := make(map[string][]string)
+	for _, sm := range allOrder {
+		allMethods[sm.Struct] = append(allMethods[sm.Struct], sm.Method.Lines()...)
+	}
+
 	var result []byte
 	var err error
 	if len(allMethods) > 0 {
-		result, err = MakeInterface(comment, pkgName, ifaceName, typeDoc, allMethods, allImports)
+		result, err = MakeInterface(comment, pkgName, ifaceName, typeDoc, allMethods, allOrder, allTypeParams, nil, allImports)
 		if err != nil {
 			log.Println("MakeInterface failed", err)
-			log.Println(comment, pkgName, ifaceName, typeDoc, allMethods, allImports)
+			log.Println(comment, pkgName, ifaceName, typeDoc, allMethods, allOrder, allImports)
 			return nil, err
 		}
 	}
 
 	return result, nil
 }
+
+// MakeFromTypes generates an interface for structName the same way Make
+// does, but sources its methods from LoadMethodSet instead of scanning
+// FuncDecls, so methods promoted from embedded fields are included. Since
+// go/types has no notion of doc comments, ifaceComment, rendered through
+// renderDoc, is the interface's only doc comment; it is not combined with
+// a parsed type doc.
+func MakeFromTypes(pattern, structName, comment, ifaceComment string, opts Options) ([]byte, error) {
+	pkgName, order, typeParam, err := LoadMethodSet(pattern, structName, false)
+	if err != nil {
+		return nil, err
+	}
+
+	sortMethods(order, opts.Sort)
+
+	methods := make(map[string][]string)
+	for _, sm := range order {
+		methods[sm.Struct] = append(methods[sm.Struct], sm.Method.Lines()...)
+	}
+
+	ifaceComments := map[string][]string{structName: renderDoc(plainDoc, ifaceComment)}
+
+	var typeParams map[string]string
+	if typeParam != "" {
+		typeParams = map[string]string{structName: typeParam}
+	}
+
+	result, err := MakeInterface(comment, pkgName, "", ifaceComments, methods, order, typeParams, nil, nil)
+	if err != nil {
+		log.Println("MakeInterface failed", err)
+		return nil, err
+	}
+
+	return result, nil
+}